@@ -0,0 +1,200 @@
+// Package auth runs the Twitch user-token OAuth flow (authorization code
+// grant) and keeps the resulting token fresh. App access tokens, which
+// is all main.go obtained before this package existed, are enough for
+// GetStreams but not for anything user- or chat-scoped, which is what
+// eventsub.go's user-token topics and the chatbot package need.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/monsieurr/isitopen/storage"
+	"golang.org/x/oauth2"
+)
+
+// storageKey is where the user token is persisted, alongside the
+// config/streamers and config/options keys the rest of the program
+// already uses.
+const storageKey = "auth/user_token"
+
+// twitchEndpoint mirrors the endpoint clientcredentials.Config already
+// points at in main.go.
+var twitchEndpoint = oauth2.Endpoint{
+	AuthURL:  "https://id.twitch.tv/oauth2/authorize",
+	TokenURL: "https://id.twitch.tv/oauth2/token",
+}
+
+// Config configures the authorization code flow.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+
+	// RedirectAddr is the host:port the local callback listener binds
+	// to; RedirectPath is appended to form the redirect URL registered
+	// with the Twitch application (e.g. http://localhost:7001/callback).
+	RedirectAddr string
+	RedirectPath string
+
+	Scopes []string
+}
+
+// Authenticator obtains and refreshes a user access token, persisting it
+// through storage.Store so it survives restarts.
+type Authenticator struct {
+	cfg       Config
+	store     storage.Store
+	oauthConf *oauth2.Config
+}
+
+// New builds an Authenticator. It does not perform any network I/O.
+func New(cfg Config, store storage.Store) *Authenticator {
+	if cfg.RedirectAddr == "" {
+		cfg.RedirectAddr = "localhost:7001"
+	}
+	if cfg.RedirectPath == "" {
+		cfg.RedirectPath = "/callback"
+	}
+	return &Authenticator{
+		cfg:   cfg,
+		store: store,
+		oauthConf: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     twitchEndpoint,
+			RedirectURL:  fmt.Sprintf("http://%s%s", cfg.RedirectAddr, cfg.RedirectPath),
+			Scopes:       cfg.Scopes,
+		},
+	}
+}
+
+// TokenSource returns an oauth2.TokenSource backed by the stored user
+// token, running the interactive consent flow first if no token has been
+// saved yet. The returned source refreshes transparently.
+func (a *Authenticator) TokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	token, err := a.loadToken()
+	if err != nil || token == nil {
+		token, err = a.runConsentFlow(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	src := a.oauthConf.TokenSource(ctx, token)
+	return &persistingTokenSource{inner: src, save: a.saveToken}, nil
+}
+
+func (a *Authenticator) loadToken() (*oauth2.Token, error) {
+	raw, found, err := a.store.Get(storageKey)
+	if err != nil || !found {
+		return nil, err
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(raw), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (a *Authenticator) saveToken(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return a.store.Put(storageKey, string(data))
+}
+
+// runConsentFlow opens the Twitch authorize page in the user's browser
+// (printing the URL as a fallback), waits for the redirect to hit a
+// local listener, and exchanges the resulting code for a token.
+func (a *Authenticator) runConsentFlow(ctx context.Context) (*oauth2.Token, error) {
+	state := fmt.Sprintf("%d", time.Now().UnixNano())
+	authURL := a.oauthConf.AuthCodeURL(state, oauth2.AccessTypeOffline)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(a.cfg.RedirectPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != state {
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			errCh <- fmt.Errorf("oauth state mismatch")
+			return
+		}
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			http.Error(w, "missing code", http.StatusBadRequest)
+			errCh <- fmt.Errorf("oauth callback missing code")
+			return
+		}
+		fmt.Fprintln(w, "Authentication complete, you can close this tab.")
+		codeCh <- code
+	})
+
+	server := &http.Server{Addr: a.cfg.RedirectAddr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+	defer server.Shutdown(context.Background())
+
+	slog.Info("auth: opening browser for Twitch login (or open manually)", "url", authURL)
+	openBrowser(authURL)
+
+	select {
+	case code := <-codeCh:
+		token, err := a.oauthConf.Exchange(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.saveToken(token); err != nil {
+			return nil, err
+		}
+		return token, nil
+	case err := <-errCh:
+		return nil, err
+	case <-time.After(5 * time.Minute):
+		return nil, fmt.Errorf("auth: timed out waiting for Twitch OAuth callback")
+	}
+}
+
+// openBrowser best-effort launches the system browser; failure just
+// leaves the user to copy/paste the URL that was already logged.
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	_ = cmd.Start()
+}
+
+// persistingTokenSource wraps an oauth2.TokenSource and re-persists the
+// token every time it's refreshed, so the refresh token on disk never
+// goes stale.
+type persistingTokenSource struct {
+	inner oauth2.TokenSource
+	save  func(*oauth2.Token) error
+}
+
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := p.inner.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.save(token); err != nil {
+		slog.Warn("auth: failed to persist refreshed token", "error", err)
+	}
+	return token, nil
+}
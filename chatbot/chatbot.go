@@ -0,0 +1,249 @@
+// Package chatbot connects to Twitch IRC using a user access token and
+// provides the REPL "say" command, auto-messages on stream-online
+// transitions, and a small set of "!command" responses. It's the
+// chat-scoped feature that the auth package's user-token flow exists to
+// unblock.
+package chatbot
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ircAddr = "irc.chat.twitch.tv:6697"
+
+	// reconnectDelay is the fixed pause between redial attempts after the
+	// connection drops, matching the retry interval eventsub.go uses for
+	// its own WebSocket connection.
+	reconnectDelay = 5 * time.Second
+
+	// readIdleTimeout bounds how long the read loop will block waiting
+	// for a line (Twitch PINGs roughly every 5 minutes) so a connection
+	// that dies without closing the socket is still noticed and
+	// reconnected instead of leaving the bot silently dark.
+	readIdleTimeout = 6 * time.Minute
+)
+
+// loginCharset matches valid Twitch username characters. Channel logins
+// reach Join/Part/Say from untrusted sources (the HTTP API's
+// POST /api/streamers, config.json edits picked up externally), so they're
+// restricted to this charset before being written into a raw IRC line.
+var loginCharset = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// stripCRLF removes carriage returns and newlines so a value can't inject
+// additional lines into the IRC connection it's written to.
+func stripCRLF(s string) string {
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.ReplaceAll(s, "\n", "")
+	return s
+}
+
+// Config configures the chat connection.
+type Config struct {
+	// Username is the bot account's own login, used for the IRC NICK.
+	Username string
+
+	// AccessToken is a user access token with chat:read/chat:edit scopes,
+	// used for the initial connection. Twitch user tokens expire in
+	// hours, so callers should keep calling SetToken with a refreshed
+	// token for every (re)connect after this one to use a live token.
+	AccessToken string
+
+	// Channels are joined automatically on connect (and expected to stay
+	// in sync with config.Streamers via Join/Part as streamers are
+	// added/removed through the REPL).
+	Channels []string
+
+	// AutoMessages maps a channel login to a message sent automatically
+	// whenever that channel's stream goes online.
+	AutoMessages map[string]string
+
+	// Commands maps a "!name" trigger (including the leading "!") to its
+	// response text.
+	Commands map[string]string
+}
+
+// Bot owns the IRC connection. mu guards both conn (replaced on every
+// reconnect) and the token/joined state a reconnect needs to
+// re-authenticate and rejoin from, so a SetToken/Join/Part call can never
+// race a reconnect using half-updated state.
+type Bot struct {
+	cfg    Config
+	mu     sync.Mutex
+	conn   net.Conn
+	token  string
+	joined map[string]struct{}
+}
+
+// Connect dials Twitch IRC, authenticates, and joins every configured
+// channel. The read loop runs in the background for the life of the
+// process and transparently redials on disconnect.
+func Connect(cfg Config) (*Bot, error) {
+	b := &Bot{cfg: cfg, token: cfg.AccessToken, joined: make(map[string]struct{})}
+	for _, channel := range cfg.Channels {
+		b.joined[loginCharset.ReplaceAllString(strings.ToLower(channel), "")] = struct{}{}
+	}
+
+	if err := b.dial(); err != nil {
+		return nil, err
+	}
+
+	go b.readLoop()
+	return b, nil
+}
+
+// dial opens a fresh TLS connection, authenticates with the most
+// recently set token, and rejoins every channel in b.joined. Called from
+// Connect and from the reconnect loop after a dropped connection.
+func (b *Bot) dial() error {
+	conn, err := tls.Dial("tcp", ircAddr, &tls.Config{})
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conn = conn
+	fmt.Fprintf(conn, "PASS oauth:%s\r\n", b.token)
+	fmt.Fprintf(conn, "NICK %s\r\n", strings.ToLower(b.cfg.Username))
+	fmt.Fprintf(conn, "CAP REQ :twitch.tv/commands twitch.tv/tags\r\n")
+	for channel := range b.joined {
+		fmt.Fprintf(conn, "JOIN #%s\r\n", channel)
+	}
+	return nil
+}
+
+// SetToken updates the token used for future (re)connections. main.go
+// calls this whenever its OAuth token source hands back a refreshed
+// token, so a reconnect after expiry authenticates with a live one
+// instead of the one Connect was first called with.
+func (b *Bot) SetToken(token string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.token = token
+}
+
+// Join adds a channel to the chat connection whenever a streamer is
+// added (the REPL's "add" command, the HTTP API, or an external config
+// edit picked up by watchConfigChanges); the startup channels are joined
+// by dial.
+func (b *Bot) Join(channel string) {
+	channel = loginCharset.ReplaceAllString(strings.ToLower(channel), "")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.joined[channel] = struct{}{}
+	fmt.Fprintf(b.conn, "JOIN #%s\r\n", channel)
+}
+
+// Part leaves a channel, mirroring the removal side of Join.
+func (b *Bot) Part(channel string) {
+	channel = loginCharset.ReplaceAllString(strings.ToLower(channel), "")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.joined, channel)
+	fmt.Fprintf(b.conn, "PART #%s\r\n", channel)
+}
+
+// Say sends msg to channel, backing the REPL's "say <channel> <msg>"
+// command.
+func (b *Bot) Say(channel, msg string) {
+	channel = loginCharset.ReplaceAllString(strings.ToLower(channel), "")
+	msg = stripCRLF(msg)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintf(b.conn, "PRIVMSG #%s :%s\r\n", channel, msg)
+}
+
+// NotifyOnline sends the configured auto-message, if any, for channel
+// going live. Intended to be called from the same place main.go's
+// dispatchNotify is called.
+func (b *Bot) NotifyOnline(channel string) {
+	msg, ok := b.cfg.AutoMessages[strings.ToLower(channel)]
+	if !ok || msg == "" {
+		return
+	}
+	b.Say(channel, msg)
+}
+
+// readLoop reads lines off the current connection until it errors out
+// (closed, expired token, or no data within readIdleTimeout), then
+// redials with a fixed delay until reconnection succeeds and keeps
+// reading from there. It never returns for the life of the process, so
+// Say/Join/Part always have a live connection to write to again once a
+// redial completes.
+func (b *Bot) readLoop() {
+	for {
+		b.mu.Lock()
+		conn := b.conn
+		b.mu.Unlock()
+
+		reader := bufio.NewReader(conn)
+		for {
+			conn.SetReadDeadline(time.Now().Add(readIdleTimeout))
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				slog.Warn("chatbot: connection lost, reconnecting", "error", err)
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+
+			if strings.HasPrefix(line, "PING") {
+				b.mu.Lock()
+				fmt.Fprintf(b.conn, "PONG%s\r\n", strings.TrimPrefix(line, "PING"))
+				b.mu.Unlock()
+				continue
+			}
+
+			b.handleLine(line)
+		}
+
+		for {
+			err := b.dial()
+			if err == nil {
+				break
+			}
+			slog.Warn("chatbot: reconnect failed, retrying", "error", err, "delay", reconnectDelay)
+			time.Sleep(reconnectDelay)
+		}
+	}
+}
+
+// handleLine looks for PRIVMSG lines starting with a configured
+// "!command" trigger and replies in the same channel.
+func (b *Bot) handleLine(line string) {
+	channel, text, ok := parsePrivmsg(line)
+	if !ok {
+		return
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return
+	}
+	if response, ok := b.cfg.Commands[fields[0]]; ok {
+		b.Say(channel, response)
+	}
+}
+
+// parsePrivmsg extracts the channel and message text from a raw IRC
+// PRIVMSG line, e.g. ":nick!user@host PRIVMSG #channel :message text".
+func parsePrivmsg(line string) (channel, text string, ok bool) {
+	const marker = " PRIVMSG #"
+	idx := strings.Index(line, marker)
+	if idx == -1 {
+		return "", "", false
+	}
+	rest := line[idx+len(marker):]
+	sep := strings.Index(rest, " :")
+	if sep == -1 {
+		return "", "", false
+	}
+	return rest[:sep], rest[sep+2:], true
+}
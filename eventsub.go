@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/monsieurr/isitopen/notify"
+	"github.com/nicklaw5/helix/v2"
+)
+
+// --- EventSub WebSocket subsystem ---
+//
+// This replaces the 30-second GetStreams poll as the primary source of
+// stream.online/stream.offline transitions. monitorStreams now only does
+// the first check; reconcileLoop below polls at a much lower frequency
+// as a reconciliation fallback in case a notification is ever missed
+// (e.g. during a reconnect race).
+
+const (
+	eventSubWSURL          = "wss://eventsub.wss.twitch.tv/ws"
+	eventSubSubscribeURL   = "https://api.twitch.tv/helix/eventsub/subscriptions"
+	reconciliationInterval = 5 * time.Minute
+)
+
+type eventSubMessage struct {
+	Metadata struct {
+		MessageType string `json:"message_type"`
+	} `json:"metadata"`
+	Payload struct {
+		Session struct {
+			ID                      string `json:"id"`
+			KeepaliveTimeoutSeconds int    `json:"keepalive_timeout_seconds"`
+			ReconnectURL            string `json:"reconnect_url"`
+		} `json:"session"`
+		Subscription struct {
+			Type string `json:"type"`
+		} `json:"subscription"`
+		Event json.RawMessage `json:"event"`
+	} `json:"payload"`
+}
+
+type streamOnlineEvent struct {
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+}
+
+type streamOfflineEvent struct {
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+}
+
+// eventSubClient owns the WebSocket connection and the set of
+// subscriptions it has asked Twitch to create against the current
+// session. It is safe for concurrent use.
+type eventSubClient struct {
+	mu            sync.Mutex
+	conn          *websocket.Conn
+	sessionID     string
+	subscriptions map[string]string // streamer login -> subscription id (per topic, keyed "login:type")
+}
+
+var eventSub *eventSubClient
+
+func newEventSubClient() *eventSubClient {
+	return &eventSubClient{subscriptions: make(map[string]string)}
+}
+
+// startEventSub connects to the EventSub WebSocket and keeps the
+// connection (and the streamer subscription set) alive for the
+// lifetime of the process.
+func startEventSub() {
+	eventSub = newEventSubClient()
+	go eventSub.run(eventSubWSURL)
+	go eventSub.reconcileLoop()
+}
+
+func (c *eventSubClient) run(url string) {
+	for {
+		nextURL, err := c.connectAndListen(url)
+		if err != nil {
+			slog.Warn("eventsub: connection error, retrying", "error", err)
+			// A reconnect URL is single-use: if the connection we
+			// followed it to then drops, re-dial the base URL and get a
+			// fresh session/reconnect URL rather than retrying this one
+			// forever.
+			url = eventSubWSURL
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		if nextURL != "" {
+			// session_reconnect: follow Twitch's replacement URL
+			// immediately instead of the base eventSubWSURL, and without
+			// the usual backoff, since the old session is already gone.
+			url = nextURL
+			continue
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// connectAndListen dials url and blocks until the connection ends. On a
+// "session_reconnect" it returns the URL the caller should dial next
+// (with a nil error) instead of reconnecting itself, so run's loop never
+// ends up with two live sessions: the old connection is closed by the
+// deferred conn.Close() before run dials the replacement.
+func (c *eventSubClient) connectAndListen(url string) (string, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return "", err
+		}
+
+		var msg eventSubMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			slog.Warn("eventsub: malformed message", "error", err)
+			continue
+		}
+
+		switch msg.Metadata.MessageType {
+		case "session_welcome":
+			c.mu.Lock()
+			c.sessionID = msg.Payload.Session.ID
+			c.mu.Unlock()
+			c.resubscribeAll()
+
+		case "session_keepalive":
+			// no-op, just proves the connection is alive
+
+		case "session_reconnect":
+			return msg.Payload.Session.ReconnectURL, nil
+
+		case "notification":
+			c.handleNotification(msg)
+
+		case "revocation":
+			slog.Warn("eventsub: subscription revoked", "type", msg.Payload.Subscription.Type)
+		}
+	}
+}
+
+func (c *eventSubClient) handleNotification(msg eventSubMessage) {
+	switch msg.Payload.Subscription.Type {
+	case "stream.online":
+		var ev streamOnlineEvent
+		if err := json.Unmarshal(msg.Payload.Event, &ev); err != nil {
+			return
+		}
+		onStreamOnline(strings.ToLower(ev.BroadcasterUserLogin))
+
+	case "stream.offline":
+		var ev streamOfflineEvent
+		if err := json.Unmarshal(msg.Payload.Event, &ev); err != nil {
+			return
+		}
+		onStreamOffline(strings.ToLower(ev.BroadcasterUserLogin))
+	}
+}
+
+// onStreamOnline marks a streamer live. The notification payload itself
+// only carries the broadcaster login, so it resolves the full
+// helix.Stream (title, game, viewer count, thumbnail) via GetStreams
+// before dispatching, rather than leaving Discord/Matrix/webhook embeds
+// empty until the next reconciliation pass fills them in.
+func onStreamOnline(login string) {
+	configMutex.Lock()
+	_, alreadyLive := liveStatus[login]
+	configMutex.Unlock()
+	if alreadyLive {
+		return
+	}
+
+	stream := helix.Stream{UserLogin: login, StartedAt: time.Now()}
+	if resp, err := helixClient.GetStreams(&helix.StreamsParams{UserLogins: []string{login}}); err != nil {
+		slog.Warn("eventsub: failed to resolve stream details", "login", login, "error", err)
+	} else if len(resp.Data.Streams) > 0 {
+		stream = resp.Data.Streams[0]
+	}
+
+	configMutex.Lock()
+	_, alreadyLive = liveStatus[login]
+	if !alreadyLive {
+		liveStatus[login] = stream
+	}
+	configMutex.Unlock()
+	if !alreadyLive {
+		publishLiveStatus(login, stream, true)
+		dispatchNotify(login, stream, notify.TransitionOnline)
+	}
+	go checkStreamerStatus()
+}
+
+// onStreamOffline mirrors the offline branch in checkStreamerStatus so
+// recording behaves identically whether we learned about the end of a
+// stream from a notification or from the reconciliation poll.
+func onStreamOffline(login string) {
+	configMutex.Lock()
+	lastKnownStream, wasLive := liveStatus[login]
+	shouldRecord := config.Options.RecordStreams
+	delete(liveStatus, login)
+	configMutex.Unlock()
+	publishLiveStatus(login, helix.Stream{}, false)
+
+	if wasLive && shouldRecord {
+		endedAt := time.Now()
+		record := StreamRecord{
+			StreamerName:    lastKnownStream.UserName,
+			Title:           lastKnownStream.Title,
+			GameName:        lastKnownStream.GameName,
+			StartedAt:       lastKnownStream.StartedAt,
+			EndedAt:         endedAt,
+			DurationMinutes: endedAt.Sub(lastKnownStream.StartedAt).Minutes(),
+		}
+		recordStream(record)
+	}
+	if wasLive {
+		dispatchNotify(login, lastKnownStream, notify.TransitionOffline)
+	}
+	go checkStreamerStatus()
+}
+
+// resubscribeAll (re)creates a stream.online/stream.offline subscription
+// for every streamer currently in the config against the current
+// session. Called after every session_welcome, i.e. on first connect and
+// on every reconnect.
+func (c *eventSubClient) resubscribeAll() {
+	configMutex.Lock()
+	streamerList := make([]string, len(config.Streamers))
+	copy(streamerList, config.Streamers)
+	configMutex.Unlock()
+
+	c.mu.Lock()
+	c.subscriptions = make(map[string]string)
+	c.mu.Unlock()
+
+	for _, login := range streamerList {
+		c.subscribeStreamer(login)
+	}
+}
+
+// subscribeStreamer creates stream.online and stream.offline
+// subscriptions for a single streamer against the current session. It is
+// called both from resubscribeAll and from the REPL's "add" command so
+// newly added streamers start receiving events immediately instead of
+// waiting for the next reconnect.
+func (c *eventSubClient) subscribeStreamer(login string) {
+	for _, topic := range []string{"stream.online", "stream.offline"} {
+		c.mu.Lock()
+		sessionID := c.sessionID
+		c.mu.Unlock()
+		if sessionID == "" {
+			continue // not connected yet; resubscribeAll will pick it up on welcome
+		}
+
+		id, err := createEventSubSubscription(topic, login, sessionID)
+		if err != nil {
+			slog.Warn("eventsub: failed to subscribe", "login", login, "topic", topic, "error", err)
+			continue
+		}
+		c.mu.Lock()
+		c.subscriptions[login+":"+topic] = id
+		c.mu.Unlock()
+	}
+}
+
+// unsubscribeStreamer tears down this streamer's subscriptions, used by
+// the REPL's "remove" command.
+func (c *eventSubClient) unsubscribeStreamer(login string) {
+	for _, topic := range []string{"stream.online", "stream.offline"} {
+		c.mu.Lock()
+		id, ok := c.subscriptions[login+":"+topic]
+		delete(c.subscriptions, login+":"+topic)
+		c.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if err := deleteEventSubSubscription(id); err != nil {
+			slog.Warn("eventsub: failed to unsubscribe", "login", login, "topic", topic, "error", err)
+		}
+	}
+}
+
+// createEventSubSubscription POSTs a subscription request for the given
+// topic/broadcaster over the WebSocket transport and returns the
+// resulting subscription ID.
+func createEventSubSubscription(topic, login, sessionID string) (string, error) {
+	userResp, err := helixClient.GetUsers(&helix.UsersParams{Logins: []string{login}})
+	if err != nil || len(userResp.Data.Users) == 0 {
+		return "", fmt.Errorf("could not resolve user id for %s: %v", login, err)
+	}
+	broadcasterID := userResp.Data.Users[0].ID
+
+	body := map[string]interface{}{
+		"type":    topic,
+		"version": "1",
+		"condition": map[string]string{
+			"broadcaster_user_id": broadcasterID,
+		},
+		"transport": map[string]string{
+			"method":     "websocket",
+			"session_id": sessionID,
+		},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, eventSubSubscribeURL, strings.NewReader(string(data)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Client-Id", clientID)
+	req.Header.Set("Authorization", "Bearer "+eventSubAccessToken())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected status %d creating %s subscription", resp.StatusCode, topic)
+	}
+
+	var parsed struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", err
+	}
+	if len(parsed.Data) == 0 {
+		return "", fmt.Errorf("no subscription id returned for %s", topic)
+	}
+	return parsed.Data[0].ID, nil
+}
+
+// eventSubAccessToken returns the user access token when one is set
+// (chat bot mode), and the app access token otherwise.
+func eventSubAccessToken() string {
+	if token := helixClient.GetUserAccessToken(); token != "" {
+		return token
+	}
+	return helixClient.GetAppAccessToken()
+}
+
+func deleteEventSubSubscription(id string) error {
+	req, err := http.NewRequest(http.MethodDelete, eventSubSubscribeURL+"?id="+id, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Client-Id", clientID)
+	req.Header.Set("Authorization", "Bearer "+eventSubAccessToken())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status %d deleting subscription %s", resp.StatusCode, id)
+	}
+	return nil
+}
+
+// reconcileLoop polls GetStreams at a much lower frequency than the old
+// 30-second loop, purely to catch anything an EventSub notification
+// might have missed (e.g. during a reconnect).
+func (c *eventSubClient) reconcileLoop() {
+	ticker := time.NewTicker(reconciliationInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		checkStreamerStatus()
+	}
+}
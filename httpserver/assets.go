@@ -0,0 +1,19 @@
+package httpserver
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed web
+var webAssets embed.FS
+
+// webFS strips the "web" prefix so the embedded index.html is served at
+// "/" instead of "/web/index.html".
+func webFS() fs.FS {
+	sub, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err) // only possible if the embed directive above is wrong
+	}
+	return sub
+}
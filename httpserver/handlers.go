@@ -0,0 +1,208 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/monsieurr/isitopen/storage"
+)
+
+// handleStreamers serves GET /api/streamers (list) and POST
+// /api/streamers (add, body {"name": "..."}).
+func (s *Server) handleStreamers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		streamers, err := s.readStreamers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, streamers)
+
+	case http.MethodPost:
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+			http.Error(w, "expected JSON body {\"name\": \"<username>\"}", http.StatusBadRequest)
+			return
+		}
+
+		streamers, err := s.readStreamers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, existing := range streamers {
+			if strings.EqualFold(existing, body.Name) {
+				writeJSON(w, streamers)
+				return
+			}
+		}
+		streamers = append(streamers, body.Name)
+		if err := s.writeStreamers(streamers); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, streamers)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleDeleteStreamer serves DELETE /api/streamers/{name}.
+func (s *Server) handleDeleteStreamer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	target := strings.TrimPrefix(r.URL.Path, "/api/streamers/")
+	if target == "" {
+		http.Error(w, "missing streamer name", http.StatusBadRequest)
+		return
+	}
+
+	streamers, err := s.readStreamers()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	remaining := make([]string, 0, len(streamers))
+	for _, existing := range streamers {
+		if !strings.EqualFold(existing, target) {
+			remaining = append(remaining, existing)
+		}
+	}
+	if err := s.writeStreamers(remaining); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, remaining)
+}
+
+// handleStatus serves GET /api/status: a snapshot of every currently-live
+// streamer, read straight out of storage.KeyLivePrefix.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := s.store.List(storage.KeyLivePrefix)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		value, found, err := s.store.Get(key)
+		if err != nil || !found {
+			continue
+		}
+		login := strings.TrimPrefix(key, storage.KeyLivePrefix)
+		status[login] = json.RawMessage(value)
+	}
+	writeJSON(w, status)
+}
+
+// handleHistory serves GET /api/history: every recorded stream session.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := s.store.List(storage.KeyHistoryPrefix)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	records := make([]json.RawMessage, 0, len(keys))
+	for _, key := range keys {
+		value, found, err := s.store.Get(key)
+		if err != nil || !found {
+			continue
+		}
+		records = append(records, json.RawMessage(value))
+	}
+	writeJSON(w, records)
+}
+
+// handleOptions serves GET/PATCH /api/options.
+func (s *Server) handleOptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		raw, found, err := s.store.Get(storage.KeyConfigOptions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			raw = "{}"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(raw))
+
+	case http.MethodPatch:
+		var patch map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		existing := map[string]json.RawMessage{}
+		if raw, found, err := s.store.Get(storage.KeyConfigOptions); err == nil && found {
+			json.Unmarshal([]byte(raw), &existing)
+		}
+		for k, v := range patch {
+			existing[k] = v
+		}
+
+		merged, err := json.Marshal(existing)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := s.store.Put(storage.KeyConfigOptions, string(merged)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(merged)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) readStreamers() ([]string, error) {
+	raw, found, err := s.store.Get(storage.KeyConfigStreamers)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []string{}, nil
+	}
+	var streamers []string
+	if err := json.Unmarshal([]byte(raw), &streamers); err != nil {
+		return nil, err
+	}
+	return streamers, nil
+}
+
+func (s *Server) writeStreamers(streamers []string) error {
+	data, err := json.Marshal(streamers)
+	if err != nil {
+		return err
+	}
+	return s.store.Put(storage.KeyConfigStreamers, string(data))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,146 @@
+// Package httpserver exposes the monitor's state over HTTP: a small JSON
+// API mirroring the REPL commands, a WebSocket feed of live/offline
+// transitions, and an embedded single-page dashboard. It talks to the
+// rest of the program exclusively through storage.Store, the same
+// integration surface the storage package was built for, so it has no
+// direct dependency on the REPL or the poll/EventSub loop.
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/monsieurr/isitopen/storage"
+)
+
+// Config configures the embedded dashboard/API server.
+type Config struct {
+	// Addr is the bind address, e.g. ":8080".
+	Addr string
+
+	// BearerToken, if non-empty, is required as "Authorization: Bearer
+	// <token>" (or a "?token=<token>" query parameter, since the
+	// WebSocket API can't set custom headers) on every /api/* and /ws
+	// request. The embedded dashboard itself at "/" is not gated, since
+	// serving it carries no data of its own; see web/index.html for how
+	// it obtains and attaches the token to its own requests.
+	BearerToken string
+}
+
+// Server is the embedded HTTP dashboard and API.
+type Server struct {
+	cfg   Config
+	store storage.Store
+	hub   *hub
+	http  *http.Server
+}
+
+// New constructs a Server. Call Start to actually listen.
+func New(cfg Config, store storage.Store) *Server {
+	s := &Server{
+		cfg:   cfg,
+		store: store,
+		hub:   newHub(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/streamers", s.withAuth(s.handleStreamers))
+	mux.HandleFunc("/api/streamers/", s.withAuth(s.handleDeleteStreamer))
+	mux.HandleFunc("/api/status", s.withAuth(s.handleStatus))
+	mux.HandleFunc("/api/history", s.withAuth(s.handleHistory))
+	mux.HandleFunc("/api/options", s.withAuth(s.handleOptions))
+	mux.HandleFunc("/ws", s.withAuth(s.handleWebSocket))
+	mux.Handle("/", http.FileServer(http.FS(webFS())))
+
+	s.http = &http.Server{Addr: cfg.Addr, Handler: mux}
+	return s
+}
+
+// Start begins listening in the background. Like the REPL and the
+// EventSub client, it's meant to run for the lifetime of the process.
+func (s *Server) Start() {
+	s.watchLiveTransitions()
+	go func() {
+		slog.Info("httpserver: dashboard listening", "addr", s.cfg.Addr)
+		if err := s.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("httpserver: listen error", "error", err)
+		}
+	}()
+}
+
+// Shutdown gracefully stops the HTTP server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+func (s *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	if s.cfg.BearerToken == "" {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		// The WebSocket API gives browser JS no way to set custom
+		// headers, so /ws also accepts the token as a query parameter.
+		if auth != "Bearer "+s.cfg.BearerToken && r.URL.Query().Get("token") != s.cfg.BearerToken {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// watchLiveTransitions subscribes to every streams/live/<login> key
+// currently known, plus the streamer list itself so newly added
+// streamers get a subscription without a restart, and fans changes out
+// to connected WebSocket clients.
+func (s *Server) watchLiveTransitions() {
+	var watchedMu sync.Mutex
+	watched := make(map[string]func())
+
+	watchLogin := func(login string) {
+		key := storage.KeyLivePrefix + login
+		watchedMu.Lock()
+		if _, ok := watched[key]; ok {
+			watchedMu.Unlock()
+			return
+		}
+		watched[key] = nil
+		watchedMu.Unlock()
+		unsubscribe := s.store.Subscribe(key, func(newValue string) {
+			event := map[string]interface{}{
+				"type":  "offline",
+				"login": login,
+			}
+			if newValue != "" {
+				event["type"] = "online"
+				var stream json.RawMessage = json.RawMessage(newValue)
+				event["stream"] = stream
+			}
+			s.hub.broadcast(event)
+		})
+		watchedMu.Lock()
+		watched[key] = unsubscribe
+		watchedMu.Unlock()
+	}
+
+	refresh := func() {
+		raw, found, err := s.store.Get(storage.KeyConfigStreamers)
+		if err != nil || !found {
+			return
+		}
+		var streamers []string
+		if err := json.Unmarshal([]byte(raw), &streamers); err != nil {
+			return
+		}
+		for _, streamer := range streamers {
+			watchLogin(strings.ToLower(streamer))
+		}
+	}
+
+	refresh()
+	s.store.Subscribe(storage.KeyConfigStreamers, func(string) { refresh() })
+}
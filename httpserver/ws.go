@@ -0,0 +1,72 @@
+package httpserver
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The dashboard is meant to be reached from the same host it's
+	// served from; same-origin checks are left to the default.
+}
+
+// hub fans a live/offline transition event out to every connected
+// WebSocket client.
+type hub struct {
+	mu      sync.Mutex
+	clients map[*websocket.Conn]struct{}
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[*websocket.Conn]struct{})}
+}
+
+func (h *hub) add(conn *websocket.Conn) {
+	h.mu.Lock()
+	h.clients[conn] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *hub) remove(conn *websocket.Conn) {
+	h.mu.Lock()
+	delete(h.clients, conn)
+	h.mu.Unlock()
+	conn.Close()
+}
+
+func (h *hub) broadcast(event interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.clients {
+		if err := conn.WriteJSON(event); err != nil {
+			slog.Warn("httpserver: dropping websocket client", "error", err)
+			delete(h.clients, conn)
+			conn.Close()
+		}
+	}
+}
+
+// handleWebSocket serves GET /ws, upgrading the connection and keeping
+// it registered with the hub until the client disconnects.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.Warn("httpserver: websocket upgrade failed", "error", err)
+		return
+	}
+	s.hub.add(conn)
+	defer s.hub.remove(conn)
+
+	// We don't expect messages from the client; block reading so we
+	// notice when it disconnects.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Structured logging. Previously every error path used log.Printf or a
+// bare fmt.Printf, which stomped on the REPL's "> " prompt mid-type since
+// nothing reprinted it afterwards. This wires a slog.Logger through the
+// whole program (set as the default so every package's slog.Info/Warn/
+// Error calls use it) that writes human-readable lines, each preceded by
+// a blank line and followed by a fresh "> " prompt, plus an optional
+// JSON copy to Config.Options.LogFile for later inspection.
+func newLogger(logFile string) *slog.Logger {
+	handlers := []slog.Handler{newTerminalHandler(os.Stdout)}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logging: could not open log file %s: %v\n", logFile, err)
+		} else {
+			handlers = append(handlers, slog.NewJSONHandler(f, nil))
+		}
+	}
+
+	return slog.New(newMultiHandler(handlers...))
+}
+
+// terminalHandler renders a slog.Record the way the rest of the REPL's
+// output in main.go looks: a blank line, a colored level tag, the message and
+// its attributes, then a fresh "> " prompt.
+type terminalHandler struct {
+	w     io.Writer
+	attrs []slog.Attr
+}
+
+func newTerminalHandler(w io.Writer) *terminalHandler {
+	return &terminalHandler{w: w}
+}
+
+func (h *terminalHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *terminalHandler) Handle(_ context.Context, r slog.Record) error {
+	color := ColorBlue
+	if r.Level >= slog.LevelError {
+		color = ColorRed
+	} else if r.Level >= slog.LevelWarn {
+		color = ColorYellow
+	}
+
+	var b strings.Builder
+	b.WriteString(r.Message)
+	for _, a := range h.attrs {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	})
+
+	_, err := fmt.Fprintf(h.w, "\n%s[%s]%s %s\n> ", color, r.Level, ColorReset, b.String())
+	return err
+}
+
+func (h *terminalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &terminalHandler{w: h.w, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *terminalHandler) WithGroup(string) slog.Handler {
+	// Groups aren't meaningful for this flat, human-readable format.
+	return h
+}
+
+// multiHandler fans a record out to every underlying handler, so the
+// terminal and the optional JSON log file both see every record.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+func newMultiHandler(handlers ...slog.Handler) *multiHandler {
+	return &multiHandler{handlers: handlers}
+}
+
+func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: next}
+}
+
+func (m *multiHandler) WithGroup(name string) slog.Handler {
+	next := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		next[i] = h.WithGroup(name)
+	}
+	return &multiHandler{handlers: next}
+}
@@ -4,9 +4,10 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"runtime"
@@ -15,7 +16,13 @@ import (
 	"time"
 
 	"github.com/joho/godotenv" // Import the godotenv library
+	"github.com/monsieurr/isitopen/auth"
+	"github.com/monsieurr/isitopen/chatbot"
+	"github.com/monsieurr/isitopen/httpserver"
+	"github.com/monsieurr/isitopen/notify"
+	"github.com/monsieurr/isitopen/storage"
 	"github.com/nicklaw5/helix/v2"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
@@ -25,15 +32,23 @@ const (
 	ColorGreen  = "\033[32m"
 	ColorYellow = "\033[33m"
 	ColorBlue   = "\033[34m"
+	ColorRed    = "\033[31m"
 	StyleBold   = "\033[1m"
 )
 
 const (
-	configFile = "config.json"
-	outputFile = "output.json"
+	// storageMarkerFile lets a deployment pin its driver by dropping a
+	// file containing the driver name (e.g. "redis") in the working
+	// directory, so `--driver`/STORAGE_DRIVER don't have to be passed on
+	// every invocation.
+	storageMarkerFile = "stul-driver"
 )
 
 const (
+	// updateInterval is no longer referenced: live/offline transitions
+	// arrive via EventSub (see eventsub.go) and trigger their own
+	// immediate redraw, and the fallback poll runs every
+	// reconciliationInterval.
 	updateInterval = 30 * time.Second
 )
 
@@ -46,6 +61,39 @@ type Config struct {
 
 type Options struct {
 	RecordStreams bool `json:"record_streams"`
+
+	// HTTPAddr, if non-empty, starts the embedded dashboard/API server
+	// (see the httpserver package) on this address, e.g. ":8080".
+	HTTPAddr string `json:"http_addr"`
+
+	// HTTPBearerToken, if set, is required as a bearer token on every
+	// /api/* and /ws request; the dashboard page at "/" is served without
+	// it.
+	HTTPBearerToken string `json:"http_bearer_token"`
+
+	// Notifiers configures outbound notifications (Discord/Matrix/generic
+	// webhook) fired on stream transitions. See the notify package.
+	Notifiers []notify.SinkConfig `json:"notifiers"`
+
+	// EnableChatBot turns on the user-token OAuth flow and the IRC chat
+	// bot (see the auth and chatbot packages). Chat-scoped features need
+	// a user token, which is why this is opt-in rather than always-on
+	// like the app-token-only GetStreams calls.
+	EnableChatBot bool `json:"enable_chat_bot"`
+
+	// ChatUsername is the bot account's own login, used for the IRC NICK.
+	ChatUsername string `json:"chat_username"`
+
+	// ChatAutoMessages maps a streamer login to a message the bot posts
+	// in that channel whenever the stream goes online.
+	ChatAutoMessages map[string]string `json:"chat_auto_messages,omitempty"`
+
+	// ChatCommands maps a "!name" trigger to its response text.
+	ChatCommands map[string]string `json:"chat_commands,omitempty"`
+
+	// LogFile, if set, receives a JSON copy of every log record in
+	// addition to the human-readable terminal output. See logging.go.
+	LogFile string `json:"log_file,omitempty"`
 }
 
 type StreamRecord struct {
@@ -62,8 +110,12 @@ type StreamRecord struct {
 var (
 	config      Config
 	helixClient *helix.Client
+	clientID    string
 	liveStatus  = make(map[string]helix.Stream)
 	configMutex = &sync.Mutex{}
+	dataStore   storage.Store
+	notifier    *notify.Manager
+	bot         *chatbot.Bot
 )
 
 // --- Terminal Management ---
@@ -79,90 +131,236 @@ func clearScreen() {
 }
 
 // --- Configuration Management ---
+//
+// Streamers and options are each kept under their own storage key
+// (storage.KeyConfigStreamers / storage.KeyConfigOptions) rather than one
+// config blob, so the HTTP API and the fsnotify watcher can update and
+// react to just the piece that changed.
 func loadConfig() error {
 	configMutex.Lock()
 	defer configMutex.Unlock()
-	file, err := ioutil.ReadFile(configFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			config = Config{Streamers: []string{}, Options: Options{RecordStreams: false}}
-			return saveConfig()
+
+	config = Config{Streamers: []string{}, Options: Options{RecordStreams: false}}
+
+	if raw, found, err := dataStore.Get(storage.KeyConfigStreamers); err != nil {
+		return err
+	} else if found {
+		if err := json.Unmarshal([]byte(raw), &config.Streamers); err != nil {
+			return err
 		}
+	}
+
+	if raw, found, err := dataStore.Get(storage.KeyConfigOptions); err != nil {
 		return err
+	} else if found {
+		if err := json.Unmarshal([]byte(raw), &config.Options); err != nil {
+			return err
+		}
 	}
-	return json.Unmarshal(file, &config)
+
+	return saveConfig()
 }
 
 func saveConfig() error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	streamers, err := json.Marshal(config.Streamers)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(configFile, data, 0644)
+	if err := dataStore.Put(storage.KeyConfigStreamers, string(streamers)); err != nil {
+		return err
+	}
+
+	options, err := json.Marshal(config.Options)
+	if err != nil {
+		return err
+	}
+	return dataStore.Put(storage.KeyConfigOptions, string(options))
 }
 
 // --- Stream Recording ---
 func recordStream(record StreamRecord) {
-	records := []StreamRecord{}
-	file, err := ioutil.ReadFile(outputFile)
-	if err == nil && len(file) > 0 {
-		json.Unmarshal(file, &records)
+	key := storage.KeyHistoryPrefix + fmt.Sprintf("%d", record.EndedAt.UnixNano())
+	data, err := json.Marshal(record)
+	if err != nil {
+		slog.Error("failed to marshal stream record", "error", err)
+		return
+	}
+	if err := dataStore.Put(key, string(data)); err != nil {
+		slog.Error("failed to save stream record", "error", err)
+		return
 	}
-	records = append(records, record)
-	data, err := json.MarshalIndent(records, "", "  ")
+	slog.Info("saved stream session", "streamer", record.StreamerName, "key", key)
+}
+
+// publishLiveStatus mirrors a liveStatus transition into the storage
+// layer under streams/live/<login> so other subsystems (the HTTP/WS
+// dashboard, Discord notifier, etc.) can Subscribe to it instead of
+// polling the in-memory map directly.
+func publishLiveStatus(login string, stream helix.Stream, isLive bool) {
+	if dataStore == nil {
+		return
+	}
+	key := storage.KeyLivePrefix + login
+	if !isLive {
+		dataStore.Delete(key)
+		return
+	}
+	data, err := json.Marshal(stream)
 	if err != nil {
-		log.Printf("Error marshaling output file: %v\n", err)
 		return
 	}
-	ioutil.WriteFile(outputFile, data, 0644)
-	fmt.Printf("\n[REC] Saved stream session for %s to %s\n> ", record.StreamerName, outputFile)
+	dataStore.Put(key, string(data))
 }
 
-// --- Animation Logic ---
+// dispatchNotify hands a stream transition to the notify.Manager, if one
+// is configured. It's a thin adapter from helix.Stream to notify.Event so
+// the notify package doesn't need to import helix.
+func dispatchNotify(login string, stream helix.Stream, transition notify.Transition) {
+	if notifier == nil {
+		return
+	}
+	displayName := stream.UserName
+	if displayName == "" {
+		displayName = login
+	}
+	notifier.Dispatch(notify.Event{
+		Login:        login,
+		DisplayName:  displayName,
+		Title:        stream.Title,
+		GameName:     stream.GameName,
+		ViewerCount:  stream.ViewerCount,
+		ThumbnailURL: stream.ThumbnailURL,
+		StartedAt:    stream.StartedAt,
+		Transition:   transition,
+	})
+
+	if bot != nil && transition == notify.TransitionOnline {
+		bot.NotifyOnline(login)
+	}
+}
 
-func animateHeader(stop chan struct{}) {
-	animationChars := []rune{'/', '\\', 'X'}
-	ticker := time.NewTicker(300 * time.Millisecond)
-	defer ticker.Stop()
+// watchConfigChanges subscribes to the streamer list key so that any
+// change made through a Store instance shared with dataStore — the HTTP
+// API's POST/DELETE handlers, or an external edit of store.json picked
+// up by the JSON driver's own fsnotify watcher (see
+// storage/json_store.go) — is reflected here without a restart: it
+// diffs the new list against config.Streamers, drops liveStatus/EventSub
+// state for anything removed, subscribes anything newly added, and
+// kicks off an immediate status check.
+func watchConfigChanges() {
+	dataStore.Subscribe(storage.KeyConfigStreamers, func(newValue string) {
+		var newStreamers []string
+		if newValue != "" {
+			if err := json.Unmarshal([]byte(newValue), &newStreamers); err != nil {
+				slog.Error("watchConfigChanges: malformed streamer list", "error", err)
+				return
+			}
+		}
 
-	i := 0
-	for {
-		select {
-		case <-ticker.C:
-			headerText := fmt.Sprintf("--- Twitch Stream Monitor --- (Last updated: %s) [", time.Now().Format("15:04:05"))
-			spinnerColumn := len(headerText) + 1
-			fmt.Printf("\033[s\033[1;%dH%c\033[u", spinnerColumn, animationChars[i%len(animationChars)])
-			i++
-		case <-stop:
-			headerText := fmt.Sprintf("--- Twitch Stream Monitor --- (Last updated: %s) [", time.Now().Format("15:04:05"))
-			spinnerColumn := len(headerText) + 1
-			fmt.Printf("\033[s\033[1;%dH \033[u", spinnerColumn)
-			return
+		configMutex.Lock()
+		oldStreamers := config.Streamers
+		config.Streamers = newStreamers
+		configMutex.Unlock()
+
+		oldSet := make(map[string]string, len(oldStreamers)) // login -> original-case name
+		for _, s := range oldStreamers {
+			oldSet[strings.ToLower(s)] = s
+		}
+		newSet := make(map[string]string, len(newStreamers))
+		for _, s := range newStreamers {
+			newSet[strings.ToLower(s)] = s
 		}
-	}
-}
 
-// --- Core Monitoring Logic ---
+		for login, name := range oldSet {
+			if _, stillPresent := newSet[login]; !stillPresent {
+				configMutex.Lock()
+				delete(liveStatus, login)
+				configMutex.Unlock()
+				if eventSub != nil {
+					eventSub.unsubscribeStreamer(login)
+				}
+				if bot != nil {
+					bot.Part(name)
+				}
+			}
+		}
+		for login, name := range newSet {
+			if _, alreadyPresent := oldSet[login]; !alreadyPresent {
+				if eventSub != nil {
+					eventSub.subscribeStreamer(login)
+				}
+				if bot != nil {
+					bot.Join(name)
+				}
+			}
+		}
 
-func monitorStreams() {
-	ticker := time.NewTicker(updateInterval)
-	defer ticker.Stop()
+		slog.Info("streamer list reloaded externally", "count", len(newStreamers))
+		go checkStreamerStatus()
+	})
+}
 
-	checkStreamerStatus() // Initial run
+// watchOptionsChanges mirrors watchConfigChanges for config/options: the
+// PATCH /api/options handler (httpserver/handlers.go) persists a merged
+// Options blob but, without this, nothing ever re-reads it into the
+// in-memory config.Options, so record_streams/notifiers changes made
+// through the API would otherwise have zero effect until restart. Options
+// only consulted at startup (HTTPAddr, EnableChatBot, the IRC/auth
+// config) still require a restart to take effect.
+func watchOptionsChanges() {
+	dataStore.Subscribe(storage.KeyConfigOptions, func(newValue string) {
+		var newOptions Options
+		if newValue != "" {
+			if err := json.Unmarshal([]byte(newValue), &newOptions); err != nil {
+				slog.Error("watchOptionsChanges: malformed options", "error", err)
+				return
+			}
+		}
 
-	for {
-		stopAnimation := make(chan struct{})
-		go animateHeader(stopAnimation)
+		configMutex.Lock()
+		config.Options = newOptions
+		configMutex.Unlock()
 
-		<-ticker.C // Wait for the 30-second timer
+		notifier = notify.NewManager(newOptions.Notifiers)
 
-		close(stopAnimation)              // Signal the animation to stop
-		time.Sleep(50 * time.Millisecond) // Give it a moment to clean up
+		slog.Info("options reloaded externally")
+	})
+}
 
-		checkStreamerStatus() // Fetch new data and redraw the screen
+// watchUserToken periodically re-fetches the user access token from src
+// and hands the result to everything that holds a copy of it. Neither
+// helixClient.SetUserAccessToken nor chatbot.Bot refreshes on its own
+// (the latter only re-authenticates on its next reconnect, using
+// whatever SetToken last gave it), so without this the token src
+// transparently refreshes underneath never reaches either of them and
+// both start failing a few hours after startup once the original token
+// expires.
+func watchUserToken(src oauth2.TokenSource) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		token, err := src.Token()
+		if err != nil {
+			slog.Warn("auth: failed to refresh user token", "error", err)
+			continue
+		}
+		helixClient.SetUserAccessToken(token.AccessToken)
+		if bot != nil {
+			bot.SetToken(token.AccessToken)
+		}
 	}
 }
 
+// --- Core Monitoring Logic ---
+
+// monitorStreams used to be a 30-second GetStreams poll loop. Live/offline
+// transitions are now pushed by EventSub (see eventsub.go), which calls
+// checkStreamerStatus itself on every notification, so this just does the
+// first draw and then gets out of the way.
+func monitorStreams() {
+	checkStreamerStatus() // Initial run
+}
+
 func checkStreamerStatus() {
 	configMutex.Lock()
 	streamerList := make([]string, len(config.Streamers))
@@ -171,7 +369,7 @@ func checkStreamerStatus() {
 	configMutex.Unlock()
 
 	clearScreen()
-	fmt.Printf("%s--- Twitch Stream Monitor --- (Last updated: %s%s%s) [ ]%s\n\n",
+	fmt.Printf("%s--- Twitch Stream Monitor --- (Last updated: %s%s%s)%s\n\n",
 		ColorBlue, StyleBold, time.Now().Format("15:04:05"), ColorReset+ColorBlue, ColorReset)
 
 	if len(streamerList) == 0 {
@@ -186,31 +384,64 @@ func checkStreamerStatus() {
 				currentlyLive[strings.ToLower(stream.UserLogin)] = stream
 			}
 
+			// liveStatus is shared with onStreamOnline/onStreamOffline
+			// (eventsub.go) and other concurrent checkStreamerStatus
+			// calls (the REPL, the reconciliation ticker), so every
+			// access has to happen under configMutex; the diff against
+			// currentlyLive is computed and liveStatus updated in one
+			// locked pass, and the resulting transitions are only acted
+			// on (recording, publishing, notifying) once unlocked.
+			type transition struct {
+				login  string
+				stream helix.Stream
+				online bool
+			}
+			var transitions []transition
+
+			configMutex.Lock()
 			for userLogin, lastKnownStream := range liveStatus {
 				if _, isStillLive := currentlyLive[userLogin]; !isStillLive {
+					delete(liveStatus, userLogin)
+					transitions = append(transitions, transition{userLogin, lastKnownStream, false})
+				}
+			}
+			for _, streamerName := range streamerList {
+				userLogin := strings.ToLower(streamerName)
+				stream, isLive := currentlyLive[userLogin]
+				if isLive {
+					if _, wasLive := liveStatus[userLogin]; !wasLive {
+						liveStatus[userLogin] = stream
+						transitions = append(transitions, transition{userLogin, stream, true})
+					}
+				}
+			}
+			configMutex.Unlock()
+
+			for _, t := range transitions {
+				if !t.online {
 					if shouldRecord {
 						endedAt := time.Now()
 						record := StreamRecord{
-							StreamerName:    lastKnownStream.UserName,
-							Title:           lastKnownStream.Title,
-							GameName:        lastKnownStream.GameName,
-							StartedAt:       lastKnownStream.StartedAt,
+							StreamerName:    t.stream.UserName,
+							Title:           t.stream.Title,
+							GameName:        t.stream.GameName,
+							StartedAt:       t.stream.StartedAt,
 							EndedAt:         endedAt,
-							DurationMinutes: endedAt.Sub(lastKnownStream.StartedAt).Minutes(),
+							DurationMinutes: endedAt.Sub(t.stream.StartedAt).Minutes(),
 						}
 						recordStream(record)
 					}
-					delete(liveStatus, userLogin)
+					publishLiveStatus(t.login, helix.Stream{}, false)
+					dispatchNotify(t.login, t.stream, notify.TransitionOffline)
+				} else {
+					publishLiveStatus(t.login, t.stream, true)
+					dispatchNotify(t.login, t.stream, notify.TransitionOnline)
 				}
 			}
 
 			for _, streamerName := range streamerList {
 				userLogin := strings.ToLower(streamerName)
-				stream, isLive := currentlyLive[userLogin]
-				if isLive {
-					if _, wasLive := liveStatus[userLogin]; !wasLive {
-						liveStatus[userLogin] = stream
-					}
+				if stream, isLive := currentlyLive[userLogin]; isLive {
 					duration := time.Since(stream.StartedAt)
 					fmt.Printf("%sO%s %s: %s%s%s [%s] (%d viewers) | Uptime: %s\n",
 						ColorGreen, ColorReset, stream.UserName, StyleBold, stream.Title, ColorReset,
@@ -250,6 +481,8 @@ func startREPL() {
 			fmt.Println("  remove <username>   - Remove a streamer from the list.")
 			fmt.Println("  list                - Show the current list of monitored streamers.")
 			fmt.Println("  toggle record       - Enable or disable recording of stream sessions.")
+			fmt.Println("  toggle notify <n>   - Enable or disable a configured notifier by name.")
+			fmt.Println("  say <channel> <msg> - Send a chat message (requires chat bot mode).")
 			fmt.Println("  options             - Show current options.")
 			fmt.Println("  status              - Force an immediate status check.")
 			fmt.Println("  exit, quit          - Exit the application.")
@@ -267,9 +500,24 @@ func startREPL() {
 				}
 				if !exists {
 					config.Streamers = append(config.Streamers, args[0])
-					saveConfig()
 				}
 				configMutex.Unlock()
+				// saveConfig is called outside the critical section: for
+				// the JSON/Badger drivers, dataStore.Put publishes to
+				// watchConfigChanges synchronously, and that subscriber
+				// also takes configMutex, so calling it while still
+				// holding the lock would deadlock.
+				if !exists {
+					if err := saveConfig(); err != nil {
+						slog.Error("add: failed to save config", "error", err)
+					}
+				}
+				if !exists && eventSub != nil {
+					eventSub.subscribeStreamer(strings.ToLower(args[0]))
+				}
+				if !exists && bot != nil {
+					bot.Join(args[0])
+				}
 				go checkStreamerStatus()
 			} else {
 				fmt.Println("Usage: add <username>")
@@ -287,9 +535,20 @@ func startREPL() {
 					}
 				}
 				config.Streamers = newStreamers
-				saveConfig()
 				delete(liveStatus, strings.ToLower(target))
 				configMutex.Unlock()
+				// See the "add" case: saveConfig must run without
+				// configMutex held, or its synchronous watchConfigChanges
+				// publish deadlocks trying to re-take it.
+				if err := saveConfig(); err != nil {
+					slog.Error("remove: failed to save config", "error", err)
+				}
+				if eventSub != nil {
+					eventSub.unsubscribeStreamer(strings.ToLower(target))
+				}
+				if bot != nil {
+					bot.Part(target)
+				}
 				go checkStreamerStatus()
 			} else {
 				fmt.Println("Usage: remove <username>")
@@ -309,11 +568,20 @@ func startREPL() {
 			if len(args) > 0 && args[0] == "record" {
 				configMutex.Lock()
 				config.Options.RecordStreams = !config.Options.RecordStreams
-				saveConfig()
-				fmt.Printf("Stream recording is now %s.\n", boolToStatus(config.Options.RecordStreams))
+				nowRecording := config.Options.RecordStreams
 				configMutex.Unlock()
+				if err := saveConfig(); err != nil {
+					slog.Error("toggle record: failed to save config", "error", err)
+				}
+				fmt.Printf("Stream recording is now %s.\n", boolToStatus(nowRecording))
+			} else if len(args) > 1 && args[0] == "notify" {
+				if enabled, found := notifier.Toggle(args[1]); found {
+					fmt.Printf("Notifier %q is now %s.\n", args[1], boolToStatus(enabled))
+				} else {
+					fmt.Printf("No notifier named %q.\n", args[1])
+				}
 			} else {
-				fmt.Println("Usage: toggle record")
+				fmt.Println("Usage: toggle record | toggle notify <name>")
 			}
 			fmt.Print("> ")
 
@@ -327,6 +595,16 @@ func startREPL() {
 		case "status":
 			go checkStreamerStatus()
 
+		case "say":
+			if bot == nil {
+				fmt.Println("Chat bot mode is not enabled.")
+			} else if len(args) < 2 {
+				fmt.Println("Usage: say <channel> <msg>")
+			} else {
+				bot.Say(args[0], strings.Join(args[1:], " "))
+			}
+			fmt.Print("> ")
+
 		case "exit", "quit":
 			fmt.Println("Exiting.")
 			return
@@ -338,6 +616,28 @@ func startREPL() {
 	}
 }
 
+// resolveStorageDriver picks the storage backend, preferring an explicit
+// --driver flag, then STORAGE_DRIVER, then the contents of the
+// storageMarkerFile in the working directory, and finally falling back
+// to the JSON file driver.
+func resolveStorageDriver() storage.Driver {
+	driverFlag := flag.String("driver", "", "storage driver: json, badger, or redis")
+	flag.Parse()
+
+	if *driverFlag != "" {
+		return storage.Driver(*driverFlag)
+	}
+	if env := os.Getenv("STORAGE_DRIVER"); env != "" {
+		return storage.Driver(env)
+	}
+	if marker, err := os.ReadFile(storageMarkerFile); err == nil {
+		if driver := strings.TrimSpace(string(marker)); driver != "" {
+			return storage.Driver(driver)
+		}
+	}
+	return storage.DriverJSON
+}
+
 // --- Main & Helpers ---
 func main() {
 	// --- NEW: Load variables from .env file ---
@@ -349,11 +649,26 @@ func main() {
 	}
 	// --- END NEW ---
 
+	dataStore, err = storage.Open(storage.Config{
+		Driver:    resolveStorageDriver(),
+		JSONDir:   ".",
+		BadgerDir: "badger-data",
+		RedisAddr: os.Getenv("REDIS_ADDR"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to open storage driver: %v", err)
+	}
+	defer dataStore.Close()
+
 	if err := loadConfig(); err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	slog.SetDefault(newLogger(config.Options.LogFile))
+	notifier = notify.NewManager(config.Options.Notifiers)
+	watchConfigChanges()
+	watchOptionsChanges()
 
-	clientID := os.Getenv("TWITCH_CLIENT_ID")
+	clientID = os.Getenv("TWITCH_CLIENT_ID")
 	clientSecret := os.Getenv("TWITCH_CLIENT_SECRET")
 	if clientID == "" || clientSecret == "" {
 		log.Fatal("TWITCH_CLIENT_ID and TWITCH_CLIENT_SECRET environment variables must be set (e.g., in a .env file).")
@@ -376,7 +691,46 @@ func main() {
 	}
 	helixClient.SetAppAccessToken(token.AccessToken)
 
+	if config.Options.EnableChatBot {
+		authenticator := auth.New(auth.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       []string{"chat:read", "chat:edit"},
+		}, dataStore)
+
+		userTokenSource, err := authenticator.TokenSource(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to obtain user access token: %v", err)
+		}
+		userToken, err := userTokenSource.Token()
+		if err != nil {
+			log.Fatalf("Failed to obtain user access token: %v", err)
+		}
+		helixClient.SetUserAccessToken(userToken.AccessToken)
+
+		bot, err = chatbot.Connect(chatbot.Config{
+			Username:     config.Options.ChatUsername,
+			AccessToken:  userToken.AccessToken,
+			Channels:     config.Streamers,
+			AutoMessages: config.Options.ChatAutoMessages,
+			Commands:     config.Options.ChatCommands,
+		})
+		if err != nil {
+			slog.Error("chatbot: failed to connect", "error", err)
+		}
+
+		go watchUserToken(userTokenSource)
+	}
+
+	if config.Options.HTTPAddr != "" {
+		httpserver.New(httpserver.Config{
+			Addr:        config.Options.HTTPAddr,
+			BearerToken: config.Options.HTTPBearerToken,
+		}, dataStore).Start()
+	}
+
 	go monitorStreams()
+	startEventSub()
 	startREPL()
 }
 
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// discordSink posts a rich embed to a Discord channel webhook.
+type discordSink struct {
+	webhookURL string
+}
+
+func (d *discordSink) send(ev Event) error {
+	thumbnail := strings.NewReplacer("{width}", "440", "{height}", "248").Replace(ev.ThumbnailURL)
+
+	title := fmt.Sprintf("%s is now live", ev.DisplayName)
+	if ev.Transition == TransitionOffline {
+		title = fmt.Sprintf("%s just went offline", ev.DisplayName)
+	}
+
+	embed := map[string]interface{}{
+		"title":       title,
+		"description": ev.Title,
+		"url":         "https://twitch.tv/" + ev.Login,
+		"fields": []map[string]interface{}{
+			{"name": "Game", "value": ev.GameName, "inline": true},
+			{"name": "Viewers", "value": fmt.Sprintf("%d", ev.ViewerCount), "inline": true},
+		},
+	}
+	if thumbnail != "" {
+		embed["image"] = map[string]string{"url": thumbnail}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"embeds": []interface{}{embed},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(d.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
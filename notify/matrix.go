@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// matrixSink sends an m.notice message to a Matrix room via the
+// client-server API.
+type matrixSink struct {
+	homeserverURL string
+	accessToken   string
+	roomID        string
+}
+
+func (m *matrixSink) send(ev Event) error {
+	text := fmt.Sprintf("%s is now live: %s", ev.DisplayName, ev.Title)
+	if ev.Transition == TransitionOffline {
+		text = fmt.Sprintf("%s just went offline", ev.DisplayName)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"msgtype": "m.notice",
+		"body":    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Matrix requires a client-generated transaction id on state-changing
+	// sends, and treats a repeat as a retried send to dedupe. ev.ID is
+	// assigned once per event by Manager.Dispatch, so sendWithRetry's
+	// attempts for the same event reuse this id instead of each minting
+	// a new one and defeating that idempotency guarantee.
+	txnID := url.QueryEscape(fmt.Sprintf("%s-%s-%d", ev.Login, ev.Transition, ev.ID))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.homeserverURL, url.PathEscape(m.roomID), txnID)
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("matrix homeserver returned status %d", resp.StatusCode)
+	}
+	return nil
+}
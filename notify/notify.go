@@ -0,0 +1,213 @@
+// Package notify fires outbound notifications (Discord, Matrix, generic
+// webhooks) whenever checkStreamerStatus sees a streamer transition
+// between offline and live. It is deliberately payload-driven rather
+// than tied to helix.Stream or StreamRecord so it has no import-cycle
+// back into package main.
+package notify
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Transition identifies which edge triggered a notification.
+type Transition string
+
+const (
+	TransitionOnline  Transition = "online"
+	TransitionOffline Transition = "offline"
+)
+
+// Event carries everything a sink might want to render.
+type Event struct {
+	Login        string
+	DisplayName  string
+	Title        string
+	GameName     string
+	ViewerCount  int
+	ThumbnailURL string
+	StartedAt    time.Time
+	Transition   Transition
+
+	// ID uniquely identifies this dispatch, set once by Dispatch before
+	// fan-out. Sinks that need an idempotent send (Matrix's transaction
+	// id) key off this instead of something recomputed per attempt, so
+	// sendWithRetry's retries of the same event reuse one id rather than
+	// each looking like a distinct message.
+	ID uint64
+}
+
+// eventSeq backs Event.ID.
+var eventSeq uint64
+
+// SinkConfig configures a single notifier. Only the fields relevant to
+// Type are used; the rest are ignored, mirroring how Options itself
+// stays a flat struct with driver-specific fields.
+type SinkConfig struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"` // "discord", "matrix", or "http_webhook"
+	Enabled bool   `json:"enabled"`
+
+	// Streamers filters which logins this sink fires for; empty means
+	// every streamer.
+	Streamers []string `json:"streamers,omitempty"`
+
+	// Transitions filters which edges this sink fires for ("online",
+	// "offline"); empty means both.
+	Transitions []string `json:"transitions,omitempty"`
+
+	// DedupSeconds suppresses repeat notifications for the same
+	// login+transition within this window, so a flapping stream doesn't
+	// spam the sink. Zero disables dedup.
+	DedupSeconds int `json:"dedup_seconds,omitempty"`
+
+	DiscordWebhookURL string `json:"discord_webhook_url,omitempty"`
+
+	MatrixHomeserverURL string `json:"matrix_homeserver_url,omitempty"`
+	MatrixAccessToken   string `json:"matrix_access_token,omitempty"`
+	MatrixRoomID        string `json:"matrix_room_id,omitempty"`
+
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// sink is the common interface every notifier type implements.
+type sink interface {
+	send(Event) error
+}
+
+// Manager owns the configured sinks and applies filtering/dedup before
+// handing an event to each one.
+type Manager struct {
+	mu        sync.Mutex
+	configs   []SinkConfig
+	sinks     map[string]sink
+	lastFired map[string]time.Time // "name:login:transition" -> last fire time
+}
+
+// NewManager builds a Manager from the configured sinks.
+func NewManager(configs []SinkConfig) *Manager {
+	m := &Manager{
+		configs:   configs,
+		sinks:     make(map[string]sink),
+		lastFired: make(map[string]time.Time),
+	}
+	for _, cfg := range configs {
+		m.sinks[cfg.Name] = buildSink(cfg)
+	}
+	return m
+}
+
+func buildSink(cfg SinkConfig) sink {
+	switch cfg.Type {
+	case "discord":
+		return &discordSink{webhookURL: cfg.DiscordWebhookURL}
+	case "matrix":
+		return &matrixSink{
+			homeserverURL: cfg.MatrixHomeserverURL,
+			accessToken:   cfg.MatrixAccessToken,
+			roomID:        cfg.MatrixRoomID,
+		}
+	case "http_webhook":
+		return &webhookSink{url: cfg.WebhookURL}
+	default:
+		return nil
+	}
+}
+
+// Toggle flips a sink's Enabled flag by name, returning the new state
+// (and false if no sink with that name exists). It backs the REPL's
+// "toggle notify <name>" command.
+func (m *Manager) Toggle(name string) (enabled bool, found bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.configs {
+		if m.configs[i].Name == name {
+			m.configs[i].Enabled = !m.configs[i].Enabled
+			return m.configs[i].Enabled, true
+		}
+	}
+	return false, false
+}
+
+// Dispatch fans ev out to every enabled, matching sink. Each send runs
+// in its own goroutine through sendWithRetry, and an error left after
+// the last attempt is dropped without logging, so one misbehaving
+// notifier can't block the others.
+func (m *Manager) Dispatch(ev Event) {
+	ev.ID = atomic.AddUint64(&eventSeq, 1)
+
+	m.mu.Lock()
+	configs := append([]SinkConfig{}, m.configs...)
+	m.mu.Unlock()
+
+	for _, cfg := range configs {
+		if !cfg.Enabled || !cfg.matches(ev) {
+			continue
+		}
+		if m.isDuplicate(cfg, ev) {
+			continue
+		}
+		s := m.sinks[cfg.Name]
+		if s == nil {
+			continue
+		}
+		go sendWithRetry(s, ev)
+	}
+}
+
+func (cfg SinkConfig) matches(ev Event) bool {
+	if len(cfg.Transitions) > 0 {
+		matched := false
+		for _, t := range cfg.Transitions {
+			if Transition(t) == ev.Transition {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(cfg.Streamers) == 0 {
+		return true
+	}
+	for _, s := range cfg.Streamers {
+		if strings.EqualFold(s, ev.Login) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) isDuplicate(cfg SinkConfig, ev Event) bool {
+	if cfg.DedupSeconds <= 0 {
+		return false
+	}
+	key := cfg.Name + ":" + ev.Login + ":" + string(ev.Transition)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	last, seen := m.lastFired[key]
+	now := time.Now()
+	if seen && now.Sub(last) < time.Duration(cfg.DedupSeconds)*time.Second {
+		return true
+	}
+	m.lastFired[key] = now
+	return false
+}
+
+// sendWithRetry gives a sink a couple of chances before giving up,
+// since webhook endpoints (Discord in particular) are occasionally
+// rate-limited.
+func sendWithRetry(s sink, ev Event) {
+	const attempts = 3
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = s.send(ev); err == nil {
+			return
+		}
+		time.Sleep(time.Duration(i+1) * time.Second)
+	}
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookSink POSTs the raw event as JSON to an arbitrary HTTP endpoint,
+// for integrations that don't need Discord/Matrix-specific formatting.
+type webhookSink struct {
+	url string
+}
+
+func (h *webhookSink) send(ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", h.url, resp.StatusCode)
+	}
+	return nil
+}
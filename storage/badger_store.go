@@ -0,0 +1,91 @@
+package storage
+
+import (
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// badgerStore backs Store with an embedded BadgerDB so a single instance
+// can persist a large number of keys (e.g. long stream history) without
+// rewriting one big JSON document on every write.
+type badgerStore struct {
+	db       *badger.DB
+	notifier *notifier
+}
+
+func newBadgerStore(dir string) (Store, error) {
+	if dir == "" {
+		dir = "badger-data"
+	}
+	opts := badger.DefaultOptions(dir).WithLogger(nil)
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &badgerStore{db: db, notifier: newNotifier()}, nil
+}
+
+func (s *badgerStore) Get(key string) (string, bool, error) {
+	var value string
+	found := true
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			found = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			value = string(val)
+			return nil
+		})
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return value, found, nil
+}
+
+func (s *badgerStore) Put(key string, value string) error {
+	err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), []byte(value))
+	})
+	if err != nil {
+		return err
+	}
+	s.notifier.publish(key, value)
+	return nil
+}
+
+func (s *badgerStore) Delete(key string) error {
+	err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+	if err != nil {
+		return err
+	}
+	s.notifier.publish(key, "")
+	return nil
+}
+
+func (s *badgerStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			keys = append(keys, string(it.Item().Key()))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *badgerStore) Subscribe(key string, fn func(string)) func() {
+	return s.notifier.subscribe(key, fn)
+}
+
+func (s *badgerStore) Close() error {
+	return s.db.Close()
+}
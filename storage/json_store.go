@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce absorbs editors that save a file in multiple syscalls
+// (write-then-rename, separate chmod, ...), so a single external edit
+// doesn't trigger several reloads back to back.
+const reloadDebounce = 250 * time.Millisecond
+
+// dataFileName is the single file the JSON driver keeps all keyed values
+// in. It replaces the old pair of standalone config.json/output.json
+// files with one document so arbitrary keys (streams/live/<login>,
+// streams/history/<id>, ...) don't each need their own file.
+const dataFileName = "store.json"
+
+// jsonStore is the default driver: every key/value pair lives in one
+// JSON document on disk. Subscribe fires for changes made through this
+// Store instance and, via the fsnotify watcher started in newJSONStore,
+// for edits another process or a human makes to that file.
+type jsonStore struct {
+	mu       sync.Mutex
+	path     string
+	values   map[string]string
+	notifier *notifier
+	watcher  *fsnotify.Watcher
+}
+
+func newJSONStore(dir string) (Store, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &jsonStore{
+		path:     filepath.Join(dir, dataFileName),
+		values:   make(map[string]string),
+		notifier: newNotifier(),
+	}
+
+	raw, err := ioutil.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s.values); err != nil {
+			return nil, err
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		// External hot-reload is a nice-to-have; don't fail Open over it.
+		slog.Warn("storage: could not start fsnotify watcher", "error", err)
+		return s, nil
+	}
+	if err := watcher.Add(dir); err != nil {
+		slog.Warn("storage: could not watch storage directory", "dir", dir, "error", err)
+		watcher.Close()
+		return s, nil
+	}
+	s.watcher = watcher
+	go s.watchExternalChanges()
+
+	return s, nil
+}
+
+// watchExternalChanges re-reads the data file on WRITE events so changes
+// made by another process (or a human editing store.json directly) are
+// picked up without a restart, debounced because many editors save in
+// more than one syscall.
+func (s *jsonStore) watchExternalChanges() {
+	var debounce *time.Timer
+	for {
+		select {
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != s.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(reloadDebounce, s.reloadFromDisk)
+			} else {
+				debounce.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("storage: fsnotify error", "error", err)
+		}
+	}
+}
+
+// reloadFromDisk re-reads store.json and publishes any key whose value
+// changed since the in-memory copy was last written. Writes made
+// through this Store instance already updated s.values before hitting
+// disk, so they produce no diff here and no duplicate notification.
+func (s *jsonStore) reloadFromDisk() {
+	raw, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	updated := make(map[string]string)
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &updated); err != nil {
+			slog.Warn("storage: external edit produced invalid JSON, ignoring", "error", err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	changed := map[string]string{}
+	for key, value := range updated {
+		if old, ok := s.values[key]; !ok || old != value {
+			changed[key] = value
+		}
+	}
+	for key := range s.values {
+		if _, ok := updated[key]; !ok {
+			changed[key] = ""
+		}
+	}
+	s.values = updated
+	s.mu.Unlock()
+
+	for key, value := range changed {
+		s.notifier.publish(key, value)
+	}
+}
+
+func (s *jsonStore) Get(key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok, nil
+}
+
+func (s *jsonStore) Put(key string, value string) error {
+	s.mu.Lock()
+	s.values[key] = value
+	err := s.flushLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notifier.publish(key, value)
+	return nil
+}
+
+func (s *jsonStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.values, key)
+	err := s.flushLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notifier.publish(key, "")
+	return nil
+}
+
+func (s *jsonStore) List(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for k := range s.values {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *jsonStore) Subscribe(key string, fn func(string)) func() {
+	return s.notifier.subscribe(key, fn)
+}
+
+func (s *jsonStore) Close() error {
+	if s.watcher != nil {
+		return s.watcher.Close()
+	}
+	return nil
+}
+
+// flushLocked rewrites the data file. Callers must hold s.mu.
+func (s *jsonStore) flushLocked() error {
+	data, err := json.MarshalIndent(s.values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,44 @@
+package storage
+
+import "sync"
+
+// notifier is an in-process pub/sub fan-out shared by the drivers that
+// don't have a native notification mechanism of their own (JSON files,
+// Badger). The Redis driver does not use it; it publishes over a Redis
+// pub/sub channel instead, so other instances see the change too.
+type notifier struct {
+	mu   sync.Mutex
+	subs map[string][]func(string)
+}
+
+func newNotifier() *notifier {
+	return &notifier{subs: make(map[string][]func(string))}
+}
+
+func (n *notifier) subscribe(key string, fn func(string)) func() {
+	n.mu.Lock()
+	n.subs[key] = append(n.subs[key], fn)
+	idx := len(n.subs[key]) - 1
+	n.mu.Unlock()
+
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		fns := n.subs[key]
+		if idx < len(fns) {
+			fns[idx] = nil
+		}
+	}
+}
+
+func (n *notifier) publish(key, value string) {
+	n.mu.Lock()
+	fns := append([]func(string){}, n.subs[key]...)
+	n.mu.Unlock()
+
+	for _, fn := range fns {
+		if fn != nil {
+			fn(value)
+		}
+	}
+}
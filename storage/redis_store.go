@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStore backs Store with Redis, which is the only driver that
+// actually shares state across multiple running instances: Put/Delete
+// publish to a Redis channel so every instance's Subscribe callbacks
+// fire, not just the one that made the change.
+type redisStore struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+const redisPubSubChannel = "isitopen:changes"
+
+func newRedisStore(addr, password string, db int) (Store, error) {
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &redisStore{client: client, ctx: ctx}, nil
+}
+
+func (s *redisStore) Get(key string) (string, bool, error) {
+	value, err := s.client.Get(s.ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisStore) Put(key string, value string) error {
+	if err := s.client.Set(s.ctx, key, value, 0).Err(); err != nil {
+		return err
+	}
+	return s.publish(key, value)
+}
+
+func (s *redisStore) Delete(key string) error {
+	if err := s.client.Del(s.ctx, key).Err(); err != nil {
+		return err
+	}
+	return s.publish(key, "")
+}
+
+func (s *redisStore) List(prefix string) ([]string, error) {
+	return s.client.Keys(s.ctx, prefix+"*").Result()
+}
+
+// Subscribe fans in the shared Redis pub/sub channel and invokes fn for
+// messages matching key. Every call opens its own subscription to keep
+// unsubscribe simple; Redis comfortably handles many subscribers to the
+// same channel.
+func (s *redisStore) Subscribe(key string, fn func(string)) func() {
+	pubsub := s.client.Subscribe(s.ctx, redisPubSubChannel)
+	ch := pubsub.Channel()
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				k, v, ok := splitChangeMessage(msg.Payload)
+				if ok && k == key {
+					fn(v)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		pubsub.Close()
+	}
+}
+
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
+
+func (s *redisStore) publish(key, value string) error {
+	return s.client.Publish(s.ctx, redisPubSubChannel, key+"\x00"+value).Err()
+}
+
+func splitChangeMessage(payload string) (key, value string, ok bool) {
+	for i := 0; i < len(payload); i++ {
+		if payload[i] == 0 {
+			return payload[:i], payload[i+1:], true
+		}
+	}
+	return "", "", false
+}
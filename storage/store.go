@@ -0,0 +1,92 @@
+// Package storage provides a pluggable key/value persistence layer with
+// change notifications. It exists so the rest of the program (config,
+// live status, recorded sessions) can stop reading and writing
+// config.json/output.json directly and instead go through a single
+// interface that can be backed by plain JSON files, an embedded
+// BadgerDB, or Redis, depending on deployment.
+package storage
+
+import "fmt"
+
+// Keys used by the rest of the program. Centralized here so every
+// driver and every caller agrees on the same layout.
+const (
+	KeyConfigStreamers = "config/streamers"
+	KeyConfigOptions   = "config/options"
+	KeyLivePrefix      = "streams/live/"
+	KeyHistoryPrefix   = "streams/history/"
+)
+
+// Store is a small key/value interface with pub/sub on top. Values are
+// passed around as their JSON-encoded string form so that every driver
+// (file, Badger, Redis) can stay serialization-agnostic.
+type Store interface {
+	// Get returns the raw value stored at key, or ("", false, nil) if no
+	// value has been set.
+	Get(key string) (value string, found bool, err error)
+
+	// Put writes value at key, overwriting any existing value, and
+	// notifies subscribers of key.
+	Put(key string, value string) error
+
+	// Delete removes key, notifying subscribers with an empty value.
+	Delete(key string) error
+
+	// List returns every key currently stored under the given prefix.
+	List(prefix string) ([]string, error)
+
+	// Subscribe registers fn to be called whenever key changes via Put or
+	// Delete, including changes made by other processes sharing the same
+	// backing store (e.g. multiple instances against the same Redis).
+	// It returns an unsubscribe function.
+	Subscribe(key string, fn func(newValue string)) (unsubscribe func())
+
+	// Close releases any resources (file handles, connections) held by
+	// the driver.
+	Close() error
+}
+
+// Driver identifies which Store implementation to construct.
+type Driver string
+
+const (
+	DriverJSON   Driver = "json"
+	DriverBadger Driver = "badger"
+	DriverRedis  Driver = "redis"
+)
+
+// Config configures Open. Fields not relevant to the chosen Driver are
+// ignored.
+type Config struct {
+	Driver Driver
+
+	// JSONDir is the directory holding the JSON driver's store.json file
+	// when Driver == DriverJSON.
+	JSONDir string
+
+	// BadgerDir is the directory where the embedded BadgerDB keeps its
+	// data files when Driver == DriverBadger.
+	BadgerDir string
+
+	// RedisAddr, RedisPassword and RedisDB configure the client when
+	// Driver == DriverRedis.
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// Open constructs the Store for cfg.Driver. Callers that don't care about
+// the driver-specific fields can leave them zero-valued; each driver
+// applies its own defaults.
+func Open(cfg Config) (Store, error) {
+	switch cfg.Driver {
+	case DriverJSON, "":
+		return newJSONStore(cfg.JSONDir)
+	case DriverBadger:
+		return newBadgerStore(cfg.BadgerDir)
+	case DriverRedis:
+		return newRedisStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}